@@ -1,16 +1,70 @@
 @echo off
+setlocal
+
+REM 传入 --dry-run 参数时只列出将要执行的安装命令，不实际安装
+set "DRY_RUN="
+set "BAD_ARGS="
+if not "%~1"=="" (
+    if /i "%~1"=="--dry-run" (set "DRY_RUN=1") else (set "BAD_ARGS=1")
+)
+if not "%~2"=="" set "BAD_ARGS=1"
+if defined BAD_ARGS (
+    echo Usage: software_install.bat [--dry-run]
+    exit /b 2
+)
 
 REM 检查是否存在软件列表文件
 if not exist "software_list.txt" (
     echo Software list file does not exist! Please create the software list file and run the script again.
-    exit /b
+    pause
+    exit /b 1
+)
+
+REM 检查 winget 是否可用（仅列出命令时不需要 winget）
+if not defined DRY_RUN (
+    where winget >nul 2>nul
+    if errorlevel 1 (
+        echo winget was not found in PATH! Please install or update "App Installer" from the Microsoft Store and run the script again.
+        echo Searched PATH entries:
+        for %%p in ("%PATH:;=" "%") do echo   %%~p
+        pause
+        exit /b 1
+    )
 )
 
-REM 逐行读取软件列表文件并安装软件
-for /f "tokens=*" %%a in (software_list.txt) do (
-    echo Installing software: %%a
-    winget install %%a 
+REM 逐行读取软件列表文件并安装软件，记录安装失败的软件
+set "FAILED="
+for /f "tokens=*" %%l in (software_list.txt) do (
+    for /f "tokens=1,* delims=@" %%a in ("%%l") do call :install "%%a" "%%b"
 )
 
+if defined DRY_RUN (
+    echo Dry run finished, no software was installed.
+    pause
+    exit /b 0
+)
+if defined FAILED (
+    echo Failed to install:%FAILED%
+    pause
+    exit /b 1
+)
 echo All software is already installed!
 pause
+exit /b 0
+
+REM 安装单个软件，列表中写作 软件ID@版本号 时安装指定版本
+:install
+set "WINGET_ARGS=%~1"
+if not "%~2"=="" set "WINGET_ARGS=--id %~1 --version %~2"
+if defined DRY_RUN (
+    echo winget install %WINGET_ARGS%
+    exit /b 0
+)
+echo Installing software: %~1 %~2
+winget install %WINGET_ARGS%
+REM winget 的失败码多为负数（如 0x8A150014 = -1978335212），需判断所有非零值；
+REM 0x8A15002B (-1978335189) 表示已安装且没有可用更新，视为成功
+if %errorlevel% equ 0 exit /b 0
+if %errorlevel% equ -1978335189 exit /b 0
+set "FAILED=%FAILED% %~1"
+exit /b 0
@@ -14,6 +14,26 @@ REM 提权命令
 %1 mshta vbscript:CreateObject("Shell.Application").ShellExecute("cmd.exe","/c %~s0 ::","","runas",1)(window.close)&&exit
 cd /d "%~dp0"
 
+REM 检查 winget 是否可用
+where winget >nul 2>nul
+if errorlevel 1 (
+    ECHO 未找到 winget，请先在 Microsoft Store 中安装或更新“应用安装程序”（App Installer）。
+    ECHO 已搜索的 PATH 路径：
+    for %%p in ("%PATH:;=" "%") do ECHO   %%~p
+    ECHO 请按任意键退出。
+    pause > nul
+    exit
+)
+
+REM 已切换为中科大源时跳过，避免重复运行时反复删除、添加列表源
+winget source list -n winget 2>nul | findstr /i /c:"https://mirrors.ustc.edu.cn/winget-source" >nul
+if not errorlevel 1 (
+    ECHO 列表源已是中科大源，无需重复切换。
+    ECHO 请按任意键退出。
+    pause > nul
+    exit
+)
+
 REM 更换列表源 为 中科大源
 winget source remove winget
 winget source add winget https://mirrors.ustc.edu.cn/winget-source
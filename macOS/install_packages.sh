@@ -1,12 +1,55 @@
 #!/bin/bash
 
-# 切换 Homebrew 源为中国源
-echo "Switching Homebrew source to China..."
-brew update-reset
-echo 'export HOMEBREW_BREW_GIT_REMOTE="https://mirrors.ustc.edu.cn/brew.git"' >> ~/.bash_profile
-echo 'export HOMEBREW_CORE_GIT_REMOTE="https://mirrors.ustc.edu.cn/homebrew-core.git"' >> ~/.bash_profile
-source ~/.bash_profile
-echo "Homebrew source switched to China."
+# 传入 --dry-run 参数时只列出将要执行的安装命令，不做任何修改；其他参数一律拒绝
+dry_run=false
+if [ $# -eq 1 ] && [ "$1" = "--dry-run" ]; then
+    dry_run=true
+elif [ $# -ne 0 ]; then
+    echo "Usage: $0 [--dry-run]"
+    exit 2
+fi
+
+# 检查 Homebrew 是否可用
+if ! command -v brew > /dev/null; then
+    echo "Homebrew (brew) was not found in PATH! Searched PATH entries:"
+    echo "$PATH" | tr ':' '\n' | sed 's/^/  /'
+    echo "Install it from https://brew.sh, or add it to PATH (e.g. eval \"\$(/opt/homebrew/bin/brew shellenv)\" on Apple Silicon), then run the script again."
+    exit 1
+fi
+
+# 切换 Homebrew 源为中国源（已写入的配置不会重复写入 ~/.bash_profile）
+brew_git_remote='export HOMEBREW_BREW_GIT_REMOTE="https://mirrors.ustc.edu.cn/brew.git"'
+core_git_remote='export HOMEBREW_CORE_GIT_REMOTE="https://mirrors.ustc.edu.cn/homebrew-core.git"'
+
+# 距上次刷新不足该分钟数时跳过 brew update-reset，可通过环境变量 BREW_REFRESH_INTERVAL_MINUTES 调整
+refresh_interval_minutes="${BREW_REFRESH_INTERVAL_MINUTES:-60}"
+refresh_stamp="$HOME/.cache/software_install_script/brew_last_refresh"
+
+if [ "$dry_run" = true ]; then
+    echo "Dry run: Homebrew source will not be switched."
+else
+    source_switched=false
+    if grep -qsxF "$brew_git_remote" ~/.bash_profile && grep -qsxF "$core_git_remote" ~/.bash_profile; then
+        echo "Homebrew source is already switched to China."
+    else
+        echo "Switching Homebrew source to China..."
+        grep -qsxF "$brew_git_remote" ~/.bash_profile || echo "$brew_git_remote" >> ~/.bash_profile
+        grep -qsxF "$core_git_remote" ~/.bash_profile || echo "$core_git_remote" >> ~/.bash_profile
+        source_switched=true
+        echo "Homebrew source switched to China."
+    fi
+    source ~/.bash_profile
+
+    # 刚切换源或距上次刷新超过间隔时才重新拉取 Homebrew 元数据
+    if [ "$source_switched" = false ] && [ -n "$(find "$refresh_stamp" -mmin -"$refresh_interval_minutes" 2>/dev/null)" ]; then
+        echo "Homebrew metadata already fresh (refreshed within $refresh_interval_minutes minutes). Skipping brew update-reset."
+    else
+        echo "Refreshing Homebrew metadata..."
+        if brew update-reset; then
+            mkdir -p "$(dirname "$refresh_stamp")" && touch "$refresh_stamp"
+        fi
+    fi
+fi
 
 # 定义软件列表文件路径
 software_list="packages.txt"
@@ -22,10 +65,16 @@ while IFS= read -r package; do
     echo "Checking if $package is installed..."
     if brew list --versions "$package" > /dev/null; then
         echo "$package is already installed. Skipping."
+    elif [ "$dry_run" = true ]; then
+        echo "brew install $package"
     else
         echo "Installing $package..."
         brew install "$package"
     fi
 done < "$software_list"
 
-echo "All software installation completed."
+if [ "$dry_run" = true ]; then
+    echo "Dry run completed. No software was installed."
+else
+    echo "All software installation completed."
+fi